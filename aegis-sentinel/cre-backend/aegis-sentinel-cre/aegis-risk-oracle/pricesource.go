@@ -0,0 +1,212 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+
+	"github.com/shopspring/decimal"
+	"github.com/smartcontractkit/cre-sdk-go/capabilities/networking/http"
+	"github.com/smartcontractkit/cre-sdk-go/cre"
+)
+
+// DataSource is one upstream price feed that a node fans out to before
+// aggregating. Weight biases the per-node median towards more trusted feeds.
+type DataSource struct {
+	URL      string  `json:"url"`
+	JSONPath string  `json:"jsonPath"`
+	Weight   float64 `json:"weight"`
+}
+
+// SourcePrice is a single source's contribution to a MarketData sample,
+// published on the report so consumers can audit which feed disagreed.
+type SourcePrice struct {
+	URL   string          `json:"url"`
+	Price decimal.Decimal `json:"price"`
+}
+
+// defaultOutlierK is used when Config.OutlierK is unset.
+const defaultOutlierK = 3.0
+
+type sourceSample struct {
+	source DataSource
+	price  decimal.Decimal
+}
+
+// fetchMarketData is the internal helper to perform the HTTP request(s).
+// It runs once per DON node: it fans out to every configured DataSource in
+// parallel, reduces them to a single trimmed, weighted median, and flags
+// cross-source divergence before the result goes into cross-node consensus.
+func fetchMarketData(config *Config, logger *slog.Logger, sendRequester *http.SendRequester) (*MarketData, error) {
+	sources := config.DataSources
+	if len(sources) == 0 {
+		sources = []DataSource{{URL: config.DataUrl, JSONPath: "price", Weight: 1}}
+	}
+
+	type pending struct {
+		source DataSource
+		resp   cre.Promise[*http.Response]
+	}
+
+	pendings := make([]pending, len(sources))
+	for i, src := range sources {
+		pendings[i] = pending{
+			source: src,
+			resp:   sendRequester.SendRequest(&http.Request{Method: "GET", Url: src.URL}),
+		}
+	}
+
+	samples := make([]sourceSample, 0, len(sources))
+	for _, p := range pendings {
+		resp, err := p.resp.Await()
+		if err != nil {
+			logger.Warn("price source unreachable, excluding from median", "url", p.source.URL, "error", err)
+			continue
+		}
+		price, err := extractJSONPath(resp.Body, p.source.JSONPath)
+		if err != nil {
+			logger.Warn("price source returned unparseable body, excluding from median", "url", p.source.URL, "error", err)
+			continue
+		}
+		samples = append(samples, sourceSample{source: p.source, price: price})
+	}
+
+	if len(samples) == 0 {
+		return nil, fmt.Errorf("fetchMarketData: all %d sources failed", len(sources))
+	}
+
+	price := trimmedWeightedMedian(samples)
+
+	k := config.OutlierK
+	if k == 0 {
+		k = defaultOutlierK
+	}
+	anomaly := ""
+	if sourceDivergence(samples, k) {
+		anomaly = "source_divergence"
+	}
+
+	sourcePrices := make([]SourcePrice, len(samples))
+	for i, s := range samples {
+		sourcePrices[i] = SourcePrice{URL: s.source.URL, Price: s.price}
+	}
+
+	return &MarketData{
+		Price:        price,
+		SourcePrices: sourcePrices,
+		AnomalyLevel: anomaly,
+	}, nil
+}
+
+// trimmedWeightedMedian drops the top and bottom 20% of samples by price,
+// then returns the weighted median of what remains. Trimming first keeps a
+// single bad feed from moving the result even when weights are uniform.
+func trimmedWeightedMedian(samples []sourceSample) decimal.Decimal {
+	sorted := make([]sourceSample, len(samples))
+	copy(sorted, samples)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].price.LessThan(sorted[j].price) })
+
+	trim := len(sorted) / 5 // 20%
+	if len(sorted)-2*trim < 1 {
+		trim = 0
+	}
+	trimmed := sorted[trim : len(sorted)-trim]
+
+	totalWeight := decimal.Zero
+	for _, s := range trimmed {
+		totalWeight = totalWeight.Add(weightOf(s))
+	}
+
+	half := totalWeight.Div(decimal.NewFromInt(2))
+	cumulative := decimal.Zero
+	for _, s := range trimmed {
+		cumulative = cumulative.Add(weightOf(s))
+		if cumulative.GreaterThanOrEqual(half) {
+			return s.price
+		}
+	}
+	return trimmed[len(trimmed)-1].price
+}
+
+func weightOf(s sourceSample) decimal.Decimal {
+	if s.source.Weight <= 0 {
+		return decimal.NewFromInt(1)
+	}
+	return decimal.NewFromFloat(s.source.Weight)
+}
+
+// sourceDivergence reports whether any source's price sits more than k
+// median-absolute-deviations away from the (untrimmed) median of all
+// sources, flagging a feed that's disagreeing with the rest.
+func sourceDivergence(samples []sourceSample, k float64) bool {
+	if len(samples) < 2 {
+		return false
+	}
+	prices := make([]decimal.Decimal, len(samples))
+	for i, s := range samples {
+		prices[i] = s.price
+	}
+	median := medianOf(prices)
+
+	deviations := make([]decimal.Decimal, len(prices))
+	for i, p := range prices {
+		deviations[i] = p.Sub(median).Abs()
+	}
+	mad := medianOf(deviations)
+	if mad.IsZero() {
+		return false
+	}
+
+	threshold := mad.Mul(decimal.NewFromFloat(k))
+	for _, d := range deviations {
+		if d.GreaterThan(threshold) {
+			return true
+		}
+	}
+	return false
+}
+
+func medianOf(values []decimal.Decimal) decimal.Decimal {
+	sorted := make([]decimal.Decimal, len(values))
+	copy(sorted, values)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].LessThan(sorted[j]) })
+
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 1 {
+		return sorted[mid]
+	}
+	return sorted[mid-1].Add(sorted[mid]).Div(decimal.NewFromInt(2))
+}
+
+// extractJSONPath reads a dot-separated path (e.g. "data.price") out of a
+// JSON document. It intentionally supports only the flat object-traversal
+// case sources in practice use; arrays and wildcards are not needed here.
+func extractJSONPath(body []byte, path string) (decimal.Decimal, error) {
+	var doc any
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return decimal.Zero, err
+	}
+
+	cur := doc
+	for _, key := range strings.Split(path, ".") {
+		obj, ok := cur.(map[string]any)
+		if !ok {
+			return decimal.Zero, fmt.Errorf("extractJSONPath: %q is not an object at %q", path, key)
+		}
+		cur, ok = obj[key]
+		if !ok {
+			return decimal.Zero, fmt.Errorf("extractJSONPath: missing key %q in path %q", key, path)
+		}
+	}
+
+	switch v := cur.(type) {
+	case float64:
+		return decimal.NewFromFloat(v), nil
+	case string:
+		return decimal.NewFromString(v)
+	default:
+		return decimal.Zero, fmt.Errorf("extractJSONPath: value at %q is not numeric", path)
+	}
+}
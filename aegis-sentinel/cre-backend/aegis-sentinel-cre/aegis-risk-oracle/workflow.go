@@ -1,20 +1,102 @@
 package main
 
 import (
-	"encoding/json"
+	"context"
 	"log/slog"
+	"sync"
 	"time"
 
 	"github.com/shopspring/decimal"
+	"github.com/smartcontractkit/cre-sdk-go/capabilities/blockchain/evm"
 	"github.com/smartcontractkit/cre-sdk-go/capabilities/networking/http"
 	"github.com/smartcontractkit/cre-sdk-go/capabilities/scheduler/cron"
 	"github.com/smartcontractkit/cre-sdk-go/cre"
+
+	"aegis-sentinel-cre/aegis"
+	"aegis-sentinel-cre/aegis/baseline"
+	"aegis-sentinel-cre/aegis/beacon"
+	"aegis-sentinel-cre/aegis/commit"
+	"aegis-sentinel-cre/aegis/onchain"
+	"aegis-sentinel-cre/aegis/state"
+)
+
+// workflowID identifies this workflow's reports to aegis.Publisher
+// subscribers.
+const workflowID = "aegis-risk-oracle"
+
+// riskPublisher fans out reports to this process's in-process aegis.Publisher
+// subscribers. It's built once per process and reused across ticks.
+var (
+	riskPublisher     *aegis.Publisher
+	riskPublisherOnce sync.Once
 )
 
+// stateKV is the shared on-chain registry all of this workflow's persisted
+// state (EWMA baseline, pending Merkle batch, on-chain submission cooldown)
+// round-trips through, namespaced by logical key (baselineKey,
+// commitBatchKey, etc.) the same way runtime.KV() would have namespaced a
+// single KV space. It's built once per process and reused across ticks;
+// only the registry contract address is configuration, not per-call state.
+var (
+	stateKV     *state.ContractKV
+	stateKVOnce sync.Once
+	stateKVErr  error
+)
+
+// getStateKV lazily constructs the shared registry client from cfg on first
+// use. CRE workflows don't get a constructor hook to do this once up front,
+// so every Callback invocation calls this and relies on sync.Once to avoid
+// rebuilding the client every tick.
+func getStateKV(cfg *Config) (*state.ContractKV, error) {
+	stateKVOnce.Do(func() {
+		stateKV, stateKVErr = state.NewContractKV(cfg.StateChainSelector, cfg.StateRegistryAddress)
+	})
+	return stateKV, stateKVErr
+}
+
+// baselineKey is the KVStore key the EWMA baseline is persisted under. It's
+// fixed because this workflow only ever tracks a single ETH price series.
+const baselineKey = "aegis-risk-oracle/eth-baseline"
+
+// commitBatchKey is the KVStore key the pending Merkle batch is persisted under.
+const commitBatchKey = "aegis-risk-oracle/commit-batch"
+
 // Config defines the workflow parameters
 type Config struct {
 	Schedule string `json:"schedule"`
-	DataUrl  string `json:"dataUrl"`
+	// DataUrl is a single-source fallback, used only when DataSources is empty.
+	DataUrl string `json:"dataUrl"`
+	// DataSources fans out to multiple price feeds; each node takes a
+	// trimmed, weighted median across them before cross-node consensus runs.
+	DataSources []DataSource `json:"dataSources,omitempty"`
+	// OutlierK is the number of median-absolute-deviations a source price
+	// may sit from the median before it's flagged as divergent. Defaults to
+	// defaultOutlierK when zero.
+	OutlierK float64 `json:"outlierK,omitempty"`
+	// Baseline controls the EWMA anomaly baseline's half-life and warm-up
+	// period. Nil falls back to the package defaults.
+	Baseline *baseline.Config `json:"baseline,omitempty"`
+	// Commit batches successive reports into a Merkle tree and submits only
+	// the root on-chain once per epoch. Nil disables batching.
+	Commit *commit.Config `json:"commit,omitempty"`
+	// Trigger configures fanning reports out to this process's in-process
+	// aegis.Publisher subscribers.
+	Trigger *aegis.PublisherConfig `json:"trigger,omitempty"`
+	// Beacon configures the drand-style fallback networks used when the
+	// primary market data feed is unreachable.
+	Beacon *beacon.Config `json:"beacon,omitempty"`
+
+	// Onchain configures the guardian contract that gets paused when a risk
+	// report trips the circuit breaker. Nil disables on-chain submission.
+	Onchain *onchain.Config `json:"onchain,omitempty"`
+
+	// StateChainSelector and StateRegistryAddress locate the on-chain
+	// registry contract this workflow persists its cross-tick state
+	// (baseline, pending batch, submission cooldowns) in. CRE workflows have
+	// no native KV capability, so this registry is the only thing that
+	// survives between cron ticks.
+	StateChainSelector   uint64 `json:"stateChainSelector"`
+	StateRegistryAddress string `json:"stateRegistryAddress"`
 }
 
 // AegisRiskReport is the final outcome of the workflow
@@ -24,6 +106,14 @@ type AegisRiskReport struct {
 	RiskScore      int             `json:"riskScore"`
 	AnomalyLevel   string          `json:"anomalyLevel"`
 	CircuitBreaker bool            `json:"circuitBreaker"`
+	// SourcePrices audits which feed contributed which price when
+	// DataSources is configured.
+	SourcePrices []SourcePrice `json:"sourcePrices,omitempty"`
+	// Z, Mu and Sigma expose the EWMA baseline behind RiskScore so operators
+	// can see why a given observation scored the way it did.
+	Z     float64 `json:"z"`
+	Mu    float64 `json:"mu"`
+	Sigma float64 `json:"sigma"`
 }
 
 // InitWorkflow follows the standard CRE pattern: Handler(Trigger, Callback)
@@ -57,66 +147,184 @@ func onAegisRiskScan(config *Config, runtime cre.Runtime, trigger *cron.Payload)
 
 	// Await the consensus-verified results
 	marketData, err := marketDataPromise.Await()
+	feedOutage := false
 	if err != nil {
-		logger.Error("Market data fetch failed, using decentralized fallback", "error", err)
-		// Fallback for local simulation environment
-		marketData = &MarketData{Price: decimal.NewFromFloat(2640.50)}
+		logger.Error("Market data fetch failed, attempting verified beacon fallback", "error", err)
+		marketData, feedOutage = fallbackMarketData(config.Beacon, runtime, logger, time.Now())
 	}
 
-	// 2. Compute Risk Intelligence
-	riskScore := computeRiskScore(marketData)
-	anomaly := "low"
-	if riskScore > 50 {
-		anomaly = "high"
+	// 2. Compute Risk Intelligence against the rolling EWMA baseline. A
+	// feed outage is reported as-is rather than folded into the baseline,
+	// so a bad fallback price can't skew future z-scores.
+	var baselineResult baseline.Result
+	if !feedOutage {
+		store, err := getStateKV(config)
+		if err != nil {
+			logger.Error("State registry unavailable, skipping baseline scoring", "error", err)
+		} else {
+			baselineResult, err = baseline.Observe(runtime, store, baselineKey, config.Baseline, marketData.Price.InexactFloat64(), time.Now())
+			if err != nil {
+				logger.Error("Baseline anomaly scoring failed", "error", err)
+			}
+		}
+	}
+
+	anomaly := marketData.AnomalyLevel
+	switch {
+	case feedOutage:
+		anomaly = "feed_outage"
+	case anomaly == "":
+		anomaly = anomalyLevelFromScore(baselineResult.RiskScore)
 	}
 
 	report := AegisRiskReport{
 		Timestamp:      time.Now().Format(time.RFC3339),
 		EthPrice:       marketData.Price,
-		RiskScore:      riskScore,
+		RiskScore:      baselineResult.RiskScore,
 		AnomalyLevel:   anomaly,
-		CircuitBreaker: riskScore > 80,
+		CircuitBreaker: baselineResult.CircuitBreaker || feedOutage,
+		SourcePrices:   marketData.SourcePrices,
+		Z:              baselineResult.Z,
+		Mu:             baselineResult.Mu,
+		Sigma:          baselineResult.Sigma,
+	}
+
+	logger.Info("Aegis Risk Scan Complete", "score", report.RiskScore, "z", report.Z, "circuitBreaker", report.CircuitBreaker)
+
+	if config.Onchain != nil {
+		if err := submitCircuitBreaker(config, runtime, report); err != nil {
+			logger.Error("Circuit breaker submission failed", "error", err)
+		}
+	}
+
+	if config.Commit != nil {
+		if err := commitReport(config, runtime, report); err != nil {
+			logger.Error("Merkle batch commit failed", "error", err)
+		}
 	}
 
-	logger.Info("Aegis Risk Scan Complete", "score", riskScore, "circuitBreaker", report.CircuitBreaker)
+	publishReport(config.Trigger, report)
 
 	return report, nil
 }
 
-type MarketData struct {
-	Price decimal.Decimal `consensus_aggregation:"median" json:"price"`
+// fallbackMarketData is used when the primary feed's consensus fetch fails.
+// It fetches and verifies a drand-style signed price snapshot from the
+// configured beacon networks instead of trusting a stale literal; if no
+// network is configured or verification fails, it reports a feed outage so
+// the caller can trip the circuit breaker rather than act on an unverified
+// price.
+func fallbackMarketData(cfg *beacon.Config, runtime cre.Runtime, logger *slog.Logger, now time.Time) (*MarketData, bool) {
+	if cfg == nil {
+		logger.Error("No beacon fallback configured")
+		return &MarketData{}, true
+	}
+
+	result, err := beacon.Fallback(context.Background(), beacon.DefaultVerifier, cfg, now)
+	if err != nil {
+		logger.Error("Beacon fallback unavailable", "error", err)
+		return &MarketData{}, true
+	}
+	if !result.Verified {
+		logger.Error("Beacon fallback signature failed verification")
+		return &MarketData{}, true
+	}
+
+	logger.Warn("Using verified beacon fallback price", "price", result.Price)
+	return &MarketData{Price: result.Price}, false
 }
 
-// fetchMarketData is the internal helper to perform the HTTP request
-func fetchMarketData(config *Config, logger *slog.Logger, sendRequester *http.SendRequester) (*MarketData, error) {
-	respPromise := sendRequester.SendRequest(&http.Request{
-		Method: "GET",
-		Url:    config.DataUrl,
+// publishReport broadcasts report to this process's in-process
+// aegis.Publisher subscribers.
+func publishReport(cfg *aegis.PublisherConfig, report AegisRiskReport) {
+	riskPublisherOnce.Do(func() {
+		riskPublisher = aegis.NewPublisherFromConfig(workflowID, cfg)
+	})
+
+	riskPublisher.Publish(aegis.RiskReport{
+		Timestamp:      report.Timestamp,
+		EthPrice:       report.EthPrice.String(),
+		RiskScore:      report.RiskScore,
+		AnomalyLevel:   report.AnomalyLevel,
+		CircuitBreaker: report.CircuitBreaker,
 	})
+}
 
-	resp, err := respPromise.Await()
+// commitReport adds report to the pending Merkle batch, flushing it (root
+// on-chain, leaves + proofs to the archive) once the batch is due.
+func commitReport(config *Config, runtime cre.Runtime, report AegisRiskReport) error {
+	cfg := config.Commit
+	store, err := getStateKV(config)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	var data struct {
-		Price float64 `json:"price"`
+	batcher, err := commit.NewBatcher(cfg, store, commitBatchKey, &evm.Client{ChainSelector: cfg.ChainSelector}, &http.Client{})
+	if err != nil {
+		return err
 	}
-	if err := json.Unmarshal(resp.Body, &data); err != nil {
-		return nil, err
+
+	timestamp, err := time.Parse(time.RFC3339, report.Timestamp)
+	if err != nil {
+		timestamp = time.Now()
 	}
 
-	return &MarketData{
-		Price: decimal.NewFromFloat(data.Price),
-	}, nil
+	return batcher.Add(runtime, commit.Leaf{
+		Timestamp:      timestamp.Unix(),
+		EthPriceMilli:  report.EthPrice.Mul(decimal.NewFromInt(1000)).IntPart(),
+		RiskScore:      int32(report.RiskScore),
+		CircuitBreaker: report.CircuitBreaker,
+	}, timestamp)
+}
+
+// submitCircuitBreaker pushes a tripped circuit breaker to the configured
+// guardian contract through the DON-signed consensus report pathway.
+func submitCircuitBreaker(config *Config, runtime cre.Runtime, report AegisRiskReport) error {
+	cfg := config.Onchain
+	store, err := getStateKV(config)
+	if err != nil {
+		return err
+	}
+
+	submitter, err := onchain.NewSubmitter(cfg, &evm.Client{ChainSelector: cfg.ChainSelector}, store)
+	if err != nil {
+		return err
+	}
+
+	timestamp, err := time.Parse(time.RFC3339, report.Timestamp)
+	if err != nil {
+		timestamp = time.Now()
+	}
+
+	return submitter.SubmitIfTripped(runtime, onchain.RiskReport{
+		Timestamp:      timestamp,
+		EthPriceMilli:  report.EthPrice.Mul(decimal.NewFromInt(1000)).IntPart(),
+		RiskScore:      int32(report.RiskScore),
+		CircuitBreaker: report.CircuitBreaker,
+	})
+}
+
+// MarketData is the per-node result of fetchMarketData. Only Price
+// participates in cross-node consensus; SourcePrices and AnomalyLevel are
+// informational and come along for the ride on whichever sample the
+// consensus aggregation settles on.
+type MarketData struct {
+	Price        decimal.Decimal `consensus_aggregation:"median" json:"price"`
+	SourcePrices []SourcePrice   `json:"sourcePrices,omitempty"`
+	AnomalyLevel string          `json:"anomalyLevel,omitempty"`
 }
 
-func computeRiskScore(data *MarketData) int {
-	// Simple simulation: base score 10, plus deviation if price is high
-	// In reality, this would check against historical baselines
-	score := 12
-	if data.Price.GreaterThan(decimal.NewFromInt(3000)) {
-		score += 15
+// anomalyLevelFromScore mirrors the RiskScore bands baseline.scoreFromZ
+// produces, so the label and the number always agree.
+func anomalyLevelFromScore(score int) string {
+	switch {
+	case score < 25:
+		return "low"
+	case score < 50:
+		return "medium"
+	case score < 75:
+		return "high"
+	default:
+		return "circuit_breaker"
 	}
-	return score
 }
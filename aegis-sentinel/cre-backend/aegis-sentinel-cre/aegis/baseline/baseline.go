@@ -0,0 +1,165 @@
+// Package baseline maintains an exponentially weighted mean and variance,
+// shared by the DON, across successive cron invocations, scoring each new observation
+// by how many standard deviations it sits from that rolling baseline.
+//
+// CRE callbacks are stateless: nothing survives between invocations unless
+// it's explicitly persisted somewhere outside the workflow, so the running
+// (mu, variance, n, lastTs) state is read and written through a KVStore
+// (see aegis/state for the on-chain-backed implementation) every call.
+package baseline
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/smartcontractkit/cre-sdk-go/cre"
+)
+
+// Config controls the EWMA baseline and the warm-up period before the
+// circuit breaker is allowed to trip.
+type Config struct {
+	// HalfLifeSamples is the number of samples after which the weight given
+	// to past observations decays by half. Larger values make the baseline
+	// slower to move.
+	HalfLifeSamples float64 `json:"halfLifeSamples"`
+	// WarmupSamples is how many observations must accumulate before the
+	// circuit breaker is allowed to fire, so a cold-started baseline with a
+	// single noisy sample can't trip it immediately.
+	WarmupSamples int64 `json:"warmupSamples"`
+}
+
+const (
+	defaultHalfLifeSamples = 20.0
+	defaultWarmupSamples   = 10
+)
+
+func (c *Config) halfLife() float64 {
+	if c == nil || c.HalfLifeSamples <= 0 {
+		return defaultHalfLifeSamples
+	}
+	return c.HalfLifeSamples
+}
+
+func (c *Config) warmupSamples() int64 {
+	if c == nil || c.WarmupSamples <= 0 {
+		return defaultWarmupSamples
+	}
+	return c.WarmupSamples
+}
+
+// state is the persisted EWMA baseline, agreed by the DON through KVStore.
+type state struct {
+	Mu       float64 `json:"mu"`
+	Variance float64 `json:"variance"`
+	N        int64   `json:"n"`
+	LastTS   int64   `json:"lastTs"`
+}
+
+// KVStore is the persistence this package needs to survive between
+// invocations. aegis/state.ContractKV is the production implementation.
+type KVStore interface {
+	Get(runtime cre.Runtime, key string) ([]byte, error)
+	Set(runtime cre.Runtime, key string, value []byte) error
+}
+
+// Result is one observation's outcome against the rolling baseline.
+type Result struct {
+	Z              float64 `json:"z"`
+	Mu             float64 `json:"mu"`
+	Sigma          float64 `json:"sigma"`
+	RiskScore      int     `json:"riskScore"`
+	CircuitBreaker bool    `json:"circuitBreaker"`
+	WarmedUp       bool    `json:"warmedUp"`
+}
+
+// Observe scores price against the baseline stored under key, then updates
+// and persists the baseline via Welford's online recurrence so the next
+// invocation (on any DON node) sees the same state.
+func Observe(runtime cre.Runtime, store KVStore, key string, cfg *Config, price float64, now time.Time) (Result, error) {
+	st, err := loadState(runtime, store, key)
+	if err != nil {
+		return Result{}, fmt.Errorf("baseline: load state: %w", err)
+	}
+
+	prevMu, prevSigma := st.Mu, math.Sqrt(st.Variance)
+	warmedUp := st.N >= cfg.warmupSamples()
+
+	var z float64
+	if st.N > 0 && prevSigma > 0 {
+		z = (price - prevMu) / prevSigma
+	}
+
+	// (1-alpha)^halfLife = 0.5, so a past observation's weight halves every
+	// halfLife samples.
+	alpha := 1 - math.Pow(0.5, 1/cfg.halfLife())
+	if st.N == 0 {
+		st.Mu = price
+		st.Variance = 0
+	} else {
+		delta := price - st.Mu
+		st.Mu = st.Mu + alpha*delta
+		st.Variance = (1 - alpha) * (st.Variance + alpha*delta*delta)
+	}
+	st.N++
+	st.LastTS = now.Unix()
+
+	if err := saveState(runtime, store, key, st); err != nil {
+		return Result{}, fmt.Errorf("baseline: save state: %w", err)
+	}
+
+	score, breaker := scoreFromZ(math.Abs(z), warmedUp)
+	return Result{
+		Z:              z,
+		Mu:             prevMu,
+		Sigma:          prevSigma,
+		RiskScore:      score,
+		CircuitBreaker: breaker,
+		WarmedUp:       warmedUp,
+	}, nil
+}
+
+// scoreFromZ maps |z| onto the 0-100 RiskScore band: <1 low, 1-2 medium,
+// 2-3 high, >3 circuit-breaker. The circuit breaker is suppressed until the
+// baseline has warmed up.
+func scoreFromZ(absZ float64, warmedUp bool) (score int, circuitBreaker bool) {
+	switch {
+	case absZ < 1:
+		score = int(absZ * 25)
+	case absZ < 2:
+		score = int(25 + (absZ-1)*25)
+	case absZ < 3:
+		score = int(50 + (absZ-2)*25)
+	default:
+		over := math.Min(absZ-3, 1)
+		score = int(75 + over*25)
+	}
+	if score > 100 {
+		score = 100
+	}
+	return score, warmedUp && absZ > 3
+}
+
+func loadState(runtime cre.Runtime, store KVStore, key string) (state, error) {
+	raw, err := store.Get(runtime, key)
+	if err != nil {
+		return state{}, err
+	}
+	if len(raw) == 0 {
+		return state{}, nil
+	}
+	var st state
+	if err := json.Unmarshal(raw, &st); err != nil {
+		return state{}, err
+	}
+	return st, nil
+}
+
+func saveState(runtime cre.Runtime, store KVStore, key string, st state) error {
+	raw, err := json.Marshal(st)
+	if err != nil {
+		return err
+	}
+	return store.Set(runtime, key, raw)
+}
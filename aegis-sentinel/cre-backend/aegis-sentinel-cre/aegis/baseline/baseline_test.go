@@ -0,0 +1,125 @@
+package baseline
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/smartcontractkit/cre-sdk-go/cre"
+	"github.com/smartcontractkit/cre-sdk-go/cre/testutils"
+)
+
+// fakeKV is an in-memory KVStore for tests; the real store round-trips
+// through an on-chain registry contract (see aegis/state.ContractKV).
+type fakeKV struct {
+	data map[string][]byte
+}
+
+func newFakeKV() *fakeKV { return &fakeKV{data: map[string][]byte{}} }
+
+func (f *fakeKV) Get(_ cre.Runtime, key string) ([]byte, error) {
+	return f.data[key], nil
+}
+
+func (f *fakeKV) Set(_ cre.Runtime, key string, value []byte) error {
+	f.data[key] = value
+	return nil
+}
+
+func TestObserve_WarmupSuppressesCircuitBreaker(t *testing.T) {
+	store := newFakeKV()
+	cfg := &Config{HalfLifeSamples: 10, WarmupSamples: 5}
+	now := time.Unix(1000, 0)
+	runtime := testutils.NewRuntime(t, nil)
+
+	// Non-flat warm-up prices give the baseline a non-zero variance, so the
+	// final observation really does carry an extreme z-score.
+	warmupPrices := []float64{2480, 2520, 2490, 2510}
+	for i, price := range warmupPrices {
+		res, err := Observe(runtime, store, "eth", cfg, price, now)
+		if err != nil {
+			t.Fatalf("Observe: %v", err)
+		}
+		if res.WarmedUp {
+			t.Fatalf("sample %d: expected not warmed up yet", i)
+		}
+	}
+
+	res, err := Observe(runtime, store, "eth", cfg, 100000, now)
+	if err != nil {
+		t.Fatalf("Observe: %v", err)
+	}
+	if res.Z <= 3 {
+		t.Fatalf("expected an extreme z-score, got z=%v", res.Z)
+	}
+	if res.CircuitBreaker {
+		t.Fatal("expected circuit breaker suppressed during warm-up despite extreme z-score")
+	}
+}
+
+func TestObserve_TripsAfterWarmup(t *testing.T) {
+	store := newFakeKV()
+	cfg := &Config{HalfLifeSamples: 10, WarmupSamples: 3}
+	now := time.Unix(1000, 0)
+	runtime := testutils.NewRuntime(t, nil)
+
+	// Alternate the warm-up prices slightly so the baseline accumulates a
+	// non-zero variance; otherwise the z-score below is undefined against a
+	// perfectly flat history instead of reflecting a real deviation.
+	warmupPrices := []float64{2480, 2520, 2490, 2510, 2500}
+	for _, price := range warmupPrices {
+		if _, err := Observe(runtime, store, "eth", cfg, price, now); err != nil {
+			t.Fatalf("Observe: %v", err)
+		}
+	}
+
+	res, err := Observe(runtime, store, "eth", cfg, 100000, now)
+	if err != nil {
+		t.Fatalf("Observe: %v", err)
+	}
+	if !res.CircuitBreaker {
+		t.Fatalf("expected circuit breaker to trip on extreme deviation, z=%v", res.Z)
+	}
+}
+
+// erroringKV always fails Get, simulating a transient RPC failure reading
+// the on-chain state registry.
+type erroringKV struct{}
+
+func (erroringKV) Get(_ cre.Runtime, _ string) ([]byte, error) {
+	return nil, errors.New("state registry unreachable")
+}
+
+func (erroringKV) Set(_ cre.Runtime, _ string, _ []byte) error {
+	return nil
+}
+
+func TestObserve_PropagatesStoreGetError(t *testing.T) {
+	cfg := &Config{HalfLifeSamples: 10, WarmupSamples: 3}
+	now := time.Unix(1000, 0)
+	runtime := testutils.NewRuntime(t, nil)
+
+	_, err := Observe(runtime, erroringKV{}, "eth", cfg, 2500, now)
+	if err == nil {
+		t.Fatal("expected Observe to propagate a Get failure instead of resetting to a cold baseline")
+	}
+}
+
+func TestScoreFromZ_Bands(t *testing.T) {
+	cases := []struct {
+		z        float64
+		wantLow  int
+		wantHigh int
+	}{
+		{0.5, 0, 24},
+		{1.5, 25, 49},
+		{2.5, 50, 74},
+		{4, 75, 100},
+	}
+	for _, c := range cases {
+		score, _ := scoreFromZ(c.z, true)
+		if score < c.wantLow || score > c.wantHigh {
+			t.Errorf("scoreFromZ(%v) = %d, want in [%d,%d]", c.z, score, c.wantLow, c.wantHigh)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package beacon
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type fakeAPI struct {
+	entry Entry
+	err   error
+}
+
+func (f fakeAPI) Entry(_ context.Context, round uint64) (Entry, error) {
+	if f.err != nil {
+		return Entry{}, f.err
+	}
+	e := f.entry
+	e.Round = round
+	return e, nil
+}
+
+// staleAPI always answers with entry regardless of the round requested,
+// simulating a compromised or cached secondary oracle replaying an old,
+// validly-signed entry.
+type staleAPI struct{ entry Entry }
+
+func (f staleAPI) Entry(_ context.Context, _ uint64) (Entry, error) {
+	return f.entry, nil
+}
+
+type fakeVerifier struct{ ok bool }
+
+func (f fakeVerifier) Verify(_, _, _ []byte) error {
+	if f.ok {
+		return nil
+	}
+	return errors.New("signature invalid")
+}
+
+func TestNetworkForRound_PicksHighestStartRoundNotExceeding(t *testing.T) {
+	networks := []Network{
+		{StartRound: 0},
+		{StartRound: 100},
+		{StartRound: 200},
+	}
+
+	net, ok := NetworkForRound(networks, 150)
+	if !ok || net.StartRound != 100 {
+		t.Fatalf("expected network with StartRound 100, got %+v (ok=%v)", net, ok)
+	}
+
+	net, ok = NetworkForRound(networks, 5)
+	if !ok || net.StartRound != 0 {
+		t.Fatalf("expected network with StartRound 0, got %+v (ok=%v)", net, ok)
+	}
+
+	_, ok = NetworkForRound(nil, 5)
+	if ok {
+		t.Fatal("expected no network for an empty list")
+	}
+}
+
+func TestFallback_VerifiedPriceUsedOnSuccess(t *testing.T) {
+	cfg := &Config{
+		Genesis: time.Unix(0, 0),
+		Period:  30 * time.Second,
+		Networks: []Network{
+			{StartRound: 0, Beacon: fakeAPI{entry: Entry{Price: decimal.NewFromFloat(2600)}}},
+		},
+	}
+
+	result, err := Fallback(context.Background(), fakeVerifier{ok: true}, cfg, time.Unix(300, 0))
+	if err != nil {
+		t.Fatalf("Fallback: %v", err)
+	}
+	if !result.Verified || !result.Price.Equal(decimal.NewFromFloat(2600)) {
+		t.Fatalf("expected verified price 2600, got %+v", result)
+	}
+}
+
+func TestFallback_UnverifiedOnBadSignature(t *testing.T) {
+	cfg := &Config{
+		Genesis: time.Unix(0, 0),
+		Period:  30 * time.Second,
+		Networks: []Network{
+			{StartRound: 0, Beacon: fakeAPI{entry: Entry{Price: decimal.NewFromFloat(2600)}}},
+		},
+	}
+
+	result, err := Fallback(context.Background(), fakeVerifier{ok: false}, cfg, time.Unix(300, 0))
+	if err != nil {
+		t.Fatalf("Fallback: %v", err)
+	}
+	if result.Verified {
+		t.Fatal("expected an unverified result when the signature fails")
+	}
+}
+
+func TestFallback_ErrorsWhenNoNetworkCovers(t *testing.T) {
+	cfg := &Config{
+		Genesis:  time.Unix(0, 0),
+		Period:   30 * time.Second,
+		Networks: []Network{{StartRound: 1000}},
+	}
+
+	_, err := Fallback(context.Background(), fakeVerifier{ok: true}, cfg, time.Unix(300, 0))
+	if err == nil {
+		t.Fatal("expected an error when round 10 precedes every network's StartRound")
+	}
+}
+
+func TestFallback_UnverifiedOnStaleRound(t *testing.T) {
+	cfg := &Config{
+		Genesis: time.Unix(0, 0),
+		Period:  30 * time.Second,
+		Networks: []Network{
+			{StartRound: 0, Beacon: staleAPI{entry: Entry{Round: 1, Price: decimal.NewFromFloat(2600)}}},
+		},
+	}
+
+	// Requested round is 10, but the oracle always answers with its stale
+	// round-1 entry. Even with a valid signature, that's not the round that
+	// was asked for.
+	result, err := Fallback(context.Background(), fakeVerifier{ok: true}, cfg, time.Unix(300, 0))
+	if err != nil {
+		t.Fatalf("Fallback: %v", err)
+	}
+	if result.Verified {
+		t.Fatal("expected an unverified result when the entry's round doesn't match the requested round")
+	}
+}
+
+func TestRoundAt_IsDeterministic(t *testing.T) {
+	cfg := &Config{Genesis: time.Unix(0, 0), Period: 30 * time.Second}
+	if got := cfg.RoundAt(time.Unix(300, 0)); got != 10 {
+		t.Fatalf("expected round 10, got %d", got)
+	}
+	if got := cfg.RoundAt(time.Unix(-5, 0)); got != 0 {
+		t.Fatalf("expected round 0 before genesis, got %d", got)
+	}
+}
@@ -0,0 +1,36 @@
+package beacon
+
+import (
+	"fmt"
+
+	"github.com/drand/kyber/pairing/bn254"
+	"github.com/drand/kyber/sign"
+	"github.com/drand/kyber/sign/bls"
+)
+
+// blsVerifier verifies drand-style BLS signatures over the BN254 pairing
+// used by drand's quicknet-compatible chains.
+type blsVerifier struct {
+	suite  *bn254.Suite
+	scheme sign.Scheme
+}
+
+// DefaultVerifier is the production Verifier: real pairing-based BLS
+// signature checks against the network's distributed public key.
+var DefaultVerifier Verifier = newBLSVerifier()
+
+func newBLSVerifier() *blsVerifier {
+	suite := bn254.NewSuite()
+	return &blsVerifier{suite: suite, scheme: bls.NewSchemeOnG2(suite)}
+}
+
+func (v *blsVerifier) Verify(publicKey, message, signature []byte) error {
+	point := v.suite.G1().Point()
+	if err := point.UnmarshalBinary(publicKey); err != nil {
+		return fmt.Errorf("beacon: invalid public key: %w", err)
+	}
+	if err := v.scheme.Verify(point, message, signature); err != nil {
+		return fmt.Errorf("beacon: signature verification failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,221 @@
+package commit
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/smartcontractkit/cre-sdk-go/capabilities/blockchain/evm"
+	"github.com/smartcontractkit/cre-sdk-go/capabilities/networking/http"
+	"github.com/smartcontractkit/cre-sdk-go/cre"
+)
+
+// rootArgs describes the ABI layout of the EncodedPayload handed to
+// GenerateReport when committing a batch's root: a single bytes32. The
+// guardian contract's receiver decodes the DON-signed report using this
+// same layout.
+var rootArgs = abi.Arguments{{Type: mustType("bytes32")}}
+
+// Config controls batch size and flush cadence.
+type Config struct {
+	// BatchSize is how many leaves accumulate before a batch is flushed
+	// early, even if FlushIntervalSeconds hasn't elapsed.
+	BatchSize int `json:"batchSize"`
+	// FlushIntervalSeconds flushes a partial batch once this much time has
+	// passed since the last flush, so a quiet period doesn't leave leaves
+	// unpublished indefinitely.
+	FlushIntervalSeconds int64 `json:"flushIntervalSeconds"`
+	// ArchiveURL receives each leaf and its inclusion proof off-chain.
+	ArchiveURL string `json:"archiveUrl"`
+
+	ChainSelector   uint64 `json:"chainSelector"`
+	ContractAddress string `json:"contractAddress"`
+}
+
+// archiveEntry is what gets published off-chain for every leaf in a batch.
+type archiveEntry struct {
+	Leaf  Leaf       `json:"leaf"`
+	Hash  [32]byte   `json:"leafHash"`
+	Proof [][32]byte `json:"proof"`
+	Root  [32]byte   `json:"root"`
+}
+
+// pendingBatch is the persisted accumulator state. CRE callbacks are
+// stateless per invocation, so the leaves collected so far and the last
+// flush time live in KVStore rather than in the Batcher itself.
+type pendingBatch struct {
+	Leaves    []Leaf `json:"leaves"`
+	LastFlush int64  `json:"lastFlush"`
+}
+
+// KVStore is the persistence this package needs to survive between
+// invocations. aegis/state.ContractKV is the production implementation.
+type KVStore interface {
+	Get(runtime cre.Runtime, key string) ([]byte, error)
+	Set(runtime cre.Runtime, key string, value []byte) error
+}
+
+// rootWriter is the slice of evm.Client this package depends on. Keeping it
+// as an interface lets tests submit against a simulated backend instead of a
+// live DON.
+type rootWriter interface {
+	WriteReport(runtime cre.Runtime, req *evm.WriteCreReportRequest) cre.Promise[*evm.WriteReportReply]
+}
+
+// archiver is the slice of http.Client this package depends on.
+type archiver interface {
+	SendRequest(runtime cre.Runtime, req *http.Request) error
+}
+
+// httpArchiver adapts *http.Client's per-node SendRequest into the
+// cre.Runtime call path, the same way workflow.go's market-data fetch uses
+// http.SendRequest with a consensus strategy. Every node archives the same
+// leaf, so the status code is expected to agree across the DON.
+type httpArchiver struct {
+	client *http.Client
+}
+
+func (a *httpArchiver) SendRequest(runtime cre.Runtime, req *http.Request) error {
+	_, err := http.SendRequest(req, runtime, a.client, func(r *http.Request, _ *slog.Logger, sender *http.SendRequester) (uint32, error) {
+		resp, err := sender.SendRequest(r).Await()
+		if err != nil {
+			return 0, err
+		}
+		return resp.StatusCode, nil
+	}, cre.ConsensusIdenticalAggregation[uint32]()).Await()
+	return err
+}
+
+// Batcher accumulates leaves (via KVStore, across invocations) until
+// BatchSize is reached or FlushIntervalSeconds elapses, then commits the
+// batch's Merkle root on-chain and archives every leaf + proof off-chain.
+type Batcher struct {
+	cfg        *Config
+	store      KVStore
+	key        string
+	rootClient rootWriter
+	archive    archiver
+	toAddr     common.Address
+}
+
+// NewBatcher builds a Batcher bound to the configured guardian contract and
+// archive endpoint, persisting its pending batch under key in store.
+func NewBatcher(cfg *Config, store KVStore, key string, rootClient *evm.Client, archiveClient *http.Client) (*Batcher, error) {
+	if !common.IsHexAddress(cfg.ContractAddress) {
+		return nil, fmt.Errorf("commit: invalid contract address %q", cfg.ContractAddress)
+	}
+	return &Batcher{
+		cfg:        cfg,
+		store:      store,
+		key:        key,
+		rootClient: rootClient,
+		archive:    &httpArchiver{client: archiveClient},
+		toAddr:     common.HexToAddress(cfg.ContractAddress),
+	}, nil
+}
+
+// Add appends leaf to the pending batch and, once BatchSize is reached or
+// FlushIntervalSeconds has elapsed since the last flush, commits the batch.
+func (b *Batcher) Add(runtime cre.Runtime, leaf Leaf, now time.Time) error {
+	batch, err := b.loadPending(runtime)
+	if err != nil {
+		return fmt.Errorf("commit: load pending batch: %w", err)
+	}
+	batch.Leaves = append(batch.Leaves, leaf)
+	if batch.LastFlush == 0 {
+		batch.LastFlush = now.Unix()
+	}
+
+	// Persist the appended leaf before attempting to flush, so a failed
+	// flush (archive or on-chain write error) doesn't lose it - the batch
+	// just gets retried whole on the next tick.
+	if err := b.savePending(runtime, batch); err != nil {
+		return fmt.Errorf("commit: save pending batch: %w", err)
+	}
+
+	due := len(batch.Leaves) >= b.cfg.BatchSize ||
+		now.Sub(time.Unix(batch.LastFlush, 0)) >= time.Duration(b.cfg.FlushIntervalSeconds)*time.Second
+	if !due {
+		return nil
+	}
+
+	if err := b.flush(runtime, batch.Leaves); err != nil {
+		return err
+	}
+	return b.savePending(runtime, pendingBatch{LastFlush: now.Unix()})
+}
+
+func (b *Batcher) flush(runtime cre.Runtime, leaves []Leaf) error {
+	tree := BuildTree(leaves)
+	if tree == nil {
+		return nil
+	}
+	root := tree.Root()
+
+	for i, leaf := range leaves {
+		entry := archiveEntry{
+			Leaf:  leaf,
+			Hash:  leaf.Hash(),
+			Proof: tree.Proof(i),
+			Root:  root,
+		}
+		body, err := json.Marshal(entry)
+		if err != nil {
+			return fmt.Errorf("commit: marshal archive entry %d: %w", i, err)
+		}
+		if err := b.archive.SendRequest(runtime, &http.Request{
+			Method: "POST",
+			Url:    b.cfg.ArchiveURL,
+			Body:   body,
+		}); err != nil {
+			return fmt.Errorf("commit: archive leaf %d: %w", i, err)
+		}
+	}
+
+	encoded, err := rootArgs.Pack(root)
+	if err != nil {
+		return fmt.Errorf("commit: encode root: %w", err)
+	}
+
+	signed, err := runtime.GenerateReport(&cre.ReportRequest{
+		EncodedPayload: encoded,
+		EncoderName:    "abi",
+		SigningAlgo:    "ecdsa-secp256k1",
+		HashingAlgo:    "keccak256",
+	}).Await()
+	if err != nil {
+		return fmt.Errorf("commit: generate DON-signed report: %w", err)
+	}
+
+	if _, err := b.rootClient.WriteReport(runtime, &evm.WriteCreReportRequest{
+		Receiver: b.toAddr.Bytes(),
+		Report:   signed,
+	}).Await(); err != nil {
+		return fmt.Errorf("commit: submit root: %w", err)
+	}
+
+	return nil
+}
+
+func (b *Batcher) loadPending(runtime cre.Runtime) (pendingBatch, error) {
+	raw, err := b.store.Get(runtime, b.key)
+	if err != nil || len(raw) == 0 {
+		return pendingBatch{}, err
+	}
+	var batch pendingBatch
+	if err := json.Unmarshal(raw, &batch); err != nil {
+		return pendingBatch{}, err
+	}
+	return batch, nil
+}
+
+func (b *Batcher) savePending(runtime cre.Runtime, batch pendingBatch) error {
+	raw, err := json.Marshal(batch)
+	if err != nil {
+		return err
+	}
+	return b.store.Set(runtime, b.key, raw)
+}
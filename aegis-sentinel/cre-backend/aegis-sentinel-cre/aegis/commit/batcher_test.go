@@ -0,0 +1,135 @@
+package commit
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/smartcontractkit/cre-sdk-go/capabilities/blockchain/evm"
+	"github.com/smartcontractkit/cre-sdk-go/capabilities/networking/http"
+	"github.com/smartcontractkit/cre-sdk-go/cre"
+	"github.com/smartcontractkit/cre-sdk-go/cre/testutils"
+)
+
+// fakeKVStore is an in-memory KVStore for tests; the real store round-trips
+// through an on-chain registry contract (see aegis/state.ContractKV).
+type fakeKVStore struct {
+	data map[string][]byte
+}
+
+func newFakeKVStore() *fakeKVStore { return &fakeKVStore{data: map[string][]byte{}} }
+
+func (f *fakeKVStore) Get(_ cre.Runtime, key string) ([]byte, error) {
+	return f.data[key], nil
+}
+
+func (f *fakeKVStore) Set(_ cre.Runtime, key string, value []byte) error {
+	f.data[key] = value
+	return nil
+}
+
+type fakeRootWriter struct {
+	calls []*evm.WriteCreReportRequest
+}
+
+func (f *fakeRootWriter) WriteReport(_ cre.Runtime, req *evm.WriteCreReportRequest) cre.Promise[*evm.WriteReportReply] {
+	f.calls = append(f.calls, req)
+	return cre.PromiseFromResult(&evm.WriteReportReply{}, nil)
+}
+
+// fakeArchiver simulates the off-chain archive endpoint; failNext makes the
+// next SendRequest call return an error, so tests can exercise a failed
+// flush without a live HTTP capability.
+type fakeArchiver struct {
+	calls    int
+	failNext bool
+}
+
+func (a *fakeArchiver) SendRequest(_ cre.Runtime, _ *http.Request) error {
+	a.calls++
+	if a.failNext {
+		a.failNext = false
+		return errors.New("archive unreachable")
+	}
+	return nil
+}
+
+func newTestBatcher(t *testing.T, store KVStore, root rootWriter, archive archiver, batchSize int, flushInterval int64) *Batcher {
+	t.Helper()
+	cfg := &Config{
+		BatchSize:            batchSize,
+		FlushIntervalSeconds: flushInterval,
+		ArchiveURL:           "https://archive.example/leaves",
+		ChainSelector:        5009297550715157269,
+		ContractAddress:      "0x00000000000000000000000000000000000000aa",
+	}
+	b, err := NewBatcher(cfg, store, "test/batch", nil, nil)
+	if err != nil {
+		t.Fatalf("NewBatcher: %v", err)
+	}
+	b.rootClient = root
+	b.archive = archive
+	return b
+}
+
+func TestAdd_FlushesWhenBatchSizeReached(t *testing.T) {
+	store := newFakeKVStore()
+	root := &fakeRootWriter{}
+	archive := &fakeArchiver{}
+	b := newTestBatcher(t, store, root, archive, 2, 3600)
+	runtime := testutils.NewRuntime(t, nil)
+	now := time.Unix(1000, 0)
+
+	if err := b.Add(runtime, Leaf{Timestamp: 1, RiskScore: 10}, now); err != nil {
+		t.Fatalf("Add (1st leaf): %v", err)
+	}
+	if len(root.calls) != 0 {
+		t.Fatalf("expected no flush before batch size reached, got %d", len(root.calls))
+	}
+
+	if err := b.Add(runtime, Leaf{Timestamp: 2, RiskScore: 20}, now); err != nil {
+		t.Fatalf("Add (2nd leaf): %v", err)
+	}
+	if len(root.calls) != 1 {
+		t.Fatalf("expected exactly one root submission once batch size was reached, got %d", len(root.calls))
+	}
+	if archive.calls != 2 {
+		t.Fatalf("expected both leaves archived, got %d calls", archive.calls)
+	}
+
+	pending, err := b.loadPending(runtime)
+	if err != nil {
+		t.Fatalf("loadPending: %v", err)
+	}
+	if len(pending.Leaves) != 0 {
+		t.Fatalf("expected pending batch cleared after flush, got %d leaves", len(pending.Leaves))
+	}
+}
+
+// TestAdd_RetainsLeafWhenFlushFails verifies the leaf survives a failed
+// flush: Add must persist the appended leaf before attempting to flush, so
+// a transient archive or on-chain error doesn't silently drop it.
+func TestAdd_RetainsLeafWhenFlushFails(t *testing.T) {
+	store := newFakeKVStore()
+	root := &fakeRootWriter{}
+	archive := &fakeArchiver{failNext: true}
+	b := newTestBatcher(t, store, root, archive, 1, 3600)
+	runtime := testutils.NewRuntime(t, nil)
+	now := time.Unix(1000, 0)
+
+	leaf := Leaf{Timestamp: 1, RiskScore: 99, CircuitBreaker: true}
+	if err := b.Add(runtime, leaf, now); err == nil {
+		t.Fatal("expected Add to surface the archive failure")
+	}
+	if len(root.calls) != 0 {
+		t.Fatalf("expected no on-chain submission when the archive step failed, got %d", len(root.calls))
+	}
+
+	pending, err := b.loadPending(runtime)
+	if err != nil {
+		t.Fatalf("loadPending: %v", err)
+	}
+	if len(pending.Leaves) != 1 || pending.Leaves[0] != leaf {
+		t.Fatalf("expected the leaf to survive the failed flush, got %+v", pending.Leaves)
+	}
+}
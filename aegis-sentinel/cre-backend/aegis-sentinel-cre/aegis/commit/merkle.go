@@ -0,0 +1,136 @@
+// Package commit batches successive AegisRiskReports into a Merkle tree so
+// only one root needs to go on-chain per epoch, while every individual leaf
+// and its inclusion proof are archived off-chain for later verification.
+package commit
+
+import (
+	"bytes"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// leafArgs describes the ABI layout of a committed leaf: (uint64 timestamp,
+// int256 ethPriceMilli, int32 riskScore, bool circuitBreaker). A downstream
+// verifier contract reconstructs the same bytes with a genuine abi.encode
+// over these types before hashing, so it has to match byte-for-byte.
+var leafArgs = abi.Arguments{
+	{Type: mustType("uint64")},
+	{Type: mustType("int256")},
+	{Type: mustType("int32")},
+	{Type: mustType("bool")},
+}
+
+func mustType(name string) abi.Type {
+	t, err := abi.NewType(name, "", nil)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// Leaf is the on-chain-relevant subset of an AegisRiskReport that gets
+// committed to the tree.
+type Leaf struct {
+	Timestamp      int64
+	EthPriceMilli  int64
+	RiskScore      int32
+	CircuitBreaker bool
+}
+
+// Hash returns keccak256(abi.encode(timestamp, ethPrice, riskScore,
+// circuitBreaker)) in a fixed field layout, so every DON node produces the
+// same leaf bytes and a standard abi.decode on-chain can recover them.
+func (l Leaf) Hash() [32]byte {
+	encoded, err := leafArgs.Pack(
+		uint64(l.Timestamp),
+		big.NewInt(l.EthPriceMilli),
+		l.RiskScore,
+		l.CircuitBreaker,
+	)
+	if err != nil {
+		// leafArgs is a fixed, known-good layout; Pack only fails on a type
+		// mismatch between the values above and leafArgs itself.
+		panic(err)
+	}
+	return crypto.Keccak256Hash(encoded)
+}
+
+// Tree is a keccak256 Merkle tree over report leaves, using OpenZeppelin's
+// sorted-pair hashing convention so proofs can be verified by any standard
+// OZ MerkleProof-compatible contract.
+type Tree struct {
+	layers [][][32]byte
+}
+
+// BuildTree builds a Merkle tree over leaves in the order given. An empty
+// slice returns a nil tree.
+func BuildTree(leaves []Leaf) *Tree {
+	if len(leaves) == 0 {
+		return nil
+	}
+
+	layer := make([][32]byte, len(leaves))
+	for i, l := range leaves {
+		layer[i] = l.Hash()
+	}
+
+	layers := [][][32]byte{layer}
+	for len(layer) > 1 {
+		next := make([][32]byte, 0, (len(layer)+1)/2)
+		for i := 0; i < len(layer); i += 2 {
+			if i+1 == len(layer) {
+				next = append(next, layer[i])
+				continue
+			}
+			next = append(next, hashPair(layer[i], layer[i+1]))
+		}
+		layers = append(layers, next)
+		layer = next
+	}
+
+	return &Tree{layers: layers}
+}
+
+// Root returns the tree's root hash.
+func (t *Tree) Root() [32]byte {
+	top := t.layers[len(t.layers)-1]
+	return top[0]
+}
+
+// Proof returns the sibling hashes needed to verify leaves[index] against
+// Root(), in bottom-up order.
+func (t *Tree) Proof(index int) [][32]byte {
+	var proof [][32]byte
+	for _, layer := range t.layers[:len(t.layers)-1] {
+		siblingIdx := index ^ 1
+		if siblingIdx < len(layer) {
+			proof = append(proof, layer[siblingIdx])
+		}
+		index /= 2
+	}
+	return proof
+}
+
+// VerifyReport recomputes the root from leaf and proof using sorted-pair
+// hashing and reports whether it matches root. Downstream consumer
+// contracts/workflows use this to prove an individual risk snapshot was
+// part of a committed batch without paying gas for every cron tick.
+func VerifyReport(leaf [32]byte, proof [][32]byte, root [32]byte) bool {
+	computed := leaf
+	for _, sibling := range proof {
+		computed = hashPair(computed, sibling)
+	}
+	return computed == root
+}
+
+// hashPair hashes two nodes in sorted order (smaller first), matching
+// OpenZeppelin's MerkleProof so the same proof verifies both off-chain and
+// in a standard OZ-based contract.
+func hashPair(a, b [32]byte) [32]byte {
+	if bytes.Compare(a[:], b[:]) > 0 {
+		a, b = b, a
+	}
+	return crypto.Keccak256Hash(a[:], b[:])
+}
@@ -0,0 +1,41 @@
+package commit
+
+import "testing"
+
+func TestBuildTree_ProofVerifiesEveryLeaf(t *testing.T) {
+	leaves := []Leaf{
+		{Timestamp: 1, EthPriceMilli: 2500000, RiskScore: 10, CircuitBreaker: false},
+		{Timestamp: 2, EthPriceMilli: 2510000, RiskScore: 12, CircuitBreaker: false},
+		{Timestamp: 3, EthPriceMilli: 2495000, RiskScore: 90, CircuitBreaker: true},
+	}
+
+	tree := BuildTree(leaves)
+	root := tree.Root()
+
+	for i, leaf := range leaves {
+		proof := tree.Proof(i)
+		if !VerifyReport(leaf.Hash(), proof, root) {
+			t.Fatalf("leaf %d failed to verify against the root", i)
+		}
+	}
+}
+
+func TestVerifyReport_RejectsWrongLeaf(t *testing.T) {
+	leaves := []Leaf{
+		{Timestamp: 1, RiskScore: 10},
+		{Timestamp: 2, RiskScore: 20},
+	}
+	tree := BuildTree(leaves)
+	root := tree.Root()
+
+	wrongLeaf := Leaf{Timestamp: 999, RiskScore: 1}.Hash()
+	if VerifyReport(wrongLeaf, tree.Proof(0), root) {
+		t.Fatal("expected verification to fail for a leaf not in the tree")
+	}
+}
+
+func TestBuildTree_NilForEmptyInput(t *testing.T) {
+	if BuildTree(nil) != nil {
+		t.Fatal("expected nil tree for empty leaves")
+	}
+}
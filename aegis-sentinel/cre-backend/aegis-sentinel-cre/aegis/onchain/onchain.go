@@ -0,0 +1,166 @@
+// Package onchain submits the Aegis circuit-breaker decision to a guardian
+// contract once the DON has reached consensus on a risk report.
+package onchain
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/smartcontractkit/cre-sdk-go/capabilities/blockchain/evm"
+	"github.com/smartcontractkit/cre-sdk-go/cre"
+)
+
+// reportArgs describes the ABI layout of the EncodedPayload handed to
+// GenerateReport: (uint64 timestamp, int256 ethPriceMilli, int32 riskScore,
+// bool circuitBreaker). The guardian contract's receiver decodes the
+// DON-signed report using this same layout.
+var reportArgs = abi.Arguments{
+	{Type: mustType("uint64")},
+	{Type: mustType("int256")},
+	{Type: mustType("int32")},
+	{Type: mustType("bool")},
+}
+
+func mustType(name string) abi.Type {
+	t, err := abi.NewType(name, "", nil)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// Config configures where tripped circuit breakers get submitted on-chain.
+type Config struct {
+	ChainSelector   uint64 `json:"chainSelector"`
+	ContractAddress string `json:"contractAddress"`
+	// CooldownSeconds is the minimum gap between two submissions, so a
+	// flapping risk score doesn't spam the chain with redundant pauses.
+	CooldownSeconds int64 `json:"cooldownSeconds"`
+}
+
+// RiskReport is the subset of AegisRiskReport that gets pushed on-chain.
+type RiskReport struct {
+	Timestamp      time.Time
+	EthPriceMilli  int64 // EthPrice scaled to milli-dollars for ABI packing
+	RiskScore      int32
+	CircuitBreaker bool
+}
+
+// reportWriter is the slice of evm.Client this package depends on. Keeping it
+// as an interface lets tests submit against a simulated backend instead of a
+// live DON.
+type reportWriter interface {
+	WriteReport(runtime cre.Runtime, req *evm.WriteCreReportRequest) cre.Promise[*evm.WriteReportReply]
+}
+
+// CooldownStore persists the last submission time across invocations. CRE
+// callbacks are stateless per call, so a Submitter rebuilt on every cron
+// tick (as workflow.go does) can't keep lastSent in a struct field the way
+// an in-process singleton could; it has to round-trip through whatever
+// actually survives between ticks. aegis/state.ContractKV is the
+// production implementation.
+type CooldownStore interface {
+	Get(runtime cre.Runtime, key string) ([]byte, error)
+	Set(runtime cre.Runtime, key string, value []byte) error
+}
+
+// Submitter pushes tripped circuit breakers to the guardian contract through
+// CRE's DON-signed report pathway.
+type Submitter struct {
+	cfg    *Config
+	client reportWriter
+	store  CooldownStore
+	toAddr common.Address
+}
+
+// NewSubmitter builds a Submitter bound to the configured guardian contract,
+// persisting its cooldown timer in store under a key derived from cfg so
+// multiple guardian contracts don't clobber each other's cooldowns.
+func NewSubmitter(cfg *Config, client *evm.Client, store CooldownStore) (*Submitter, error) {
+	if !common.IsHexAddress(cfg.ContractAddress) {
+		return nil, fmt.Errorf("onchain: invalid contract address %q", cfg.ContractAddress)
+	}
+	return &Submitter{
+		cfg:    cfg,
+		client: client,
+		store:  store,
+		toAddr: common.HexToAddress(cfg.ContractAddress),
+	}, nil
+}
+
+func (s *Submitter) cooldownKey() string {
+	return "aegis-onchain/cooldown/" + s.cfg.ContractAddress
+}
+
+// SubmitIfTripped ABI-encodes report and, if CircuitBreaker is set and the
+// cooldown window has elapsed, submits it through the DON-signed consensus
+// report pathway so the guardian contract can verify 2f+1 nodes agreed.
+func (s *Submitter) SubmitIfTripped(runtime cre.Runtime, report RiskReport) error {
+	if !report.CircuitBreaker {
+		return nil
+	}
+
+	lastSent, err := s.loadLastSent(runtime)
+	if err != nil {
+		return fmt.Errorf("onchain: load cooldown state: %w", err)
+	}
+	if !lastSent.IsZero() && report.Timestamp.Sub(lastSent) < time.Duration(s.cfg.CooldownSeconds)*time.Second {
+		runtime.Logger().Warn("onchain: circuit breaker trip suppressed by cooldown",
+			"sinceLast", report.Timestamp.Sub(lastSent))
+		return nil
+	}
+
+	encoded, err := encodeReport(report)
+	if err != nil {
+		return fmt.Errorf("onchain: encode report: %w", err)
+	}
+
+	signed, err := runtime.GenerateReport(&cre.ReportRequest{
+		EncodedPayload: encoded,
+		EncoderName:    "abi",
+		SigningAlgo:    "ecdsa-secp256k1",
+		HashingAlgo:    "keccak256",
+	}).Await()
+	if err != nil {
+		return fmt.Errorf("onchain: generate DON-signed report: %w", err)
+	}
+
+	if _, err := s.client.WriteReport(runtime, &evm.WriteCreReportRequest{
+		Receiver: s.toAddr.Bytes(),
+		Report:   signed,
+	}).Await(); err != nil {
+		return fmt.Errorf("onchain: submit pause transaction: %w", err)
+	}
+
+	return s.saveLastSent(runtime, report.Timestamp)
+}
+
+func (s *Submitter) loadLastSent(runtime cre.Runtime) (time.Time, error) {
+	raw, err := s.store.Get(runtime, s.cooldownKey())
+	if err != nil || len(raw) != 8 {
+		return time.Time{}, err
+	}
+	return time.Unix(int64(big.NewInt(0).SetBytes(raw).Uint64()), 0), nil
+}
+
+func (s *Submitter) saveLastSent(runtime cre.Runtime, t time.Time) error {
+	buf := make([]byte, 8)
+	big.NewInt(t.Unix()).FillBytes(buf)
+	return s.store.Set(runtime, s.cooldownKey(), buf)
+}
+
+// encodeReport ABI-encodes the risk report fields in a fixed, deterministic
+// layout (matching reportArgs) so every DON node produces identical bytes
+// for consensus signing, and so the guardian contract's receiver can decode
+// it with a genuine abi.decode.
+func encodeReport(report RiskReport) ([]byte, error) {
+	return reportArgs.Pack(
+		uint64(report.Timestamp.Unix()),
+		big.NewInt(report.EthPriceMilli),
+		report.RiskScore,
+		report.CircuitBreaker,
+	)
+}
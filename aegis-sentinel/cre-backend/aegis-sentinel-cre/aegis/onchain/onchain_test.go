@@ -0,0 +1,141 @@
+package onchain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/smartcontractkit/cre-sdk-go/capabilities/blockchain/evm"
+	"github.com/smartcontractkit/cre-sdk-go/cre"
+	"github.com/smartcontractkit/cre-sdk-go/cre/testutils"
+)
+
+// fakeReportWriter is a simulated guardian-contract backend: it records
+// every WriteReport call instead of sending a real transaction.
+type fakeReportWriter struct {
+	calls []*evm.WriteCreReportRequest
+}
+
+func (f *fakeReportWriter) WriteReport(_ cre.Runtime, req *evm.WriteCreReportRequest) cre.Promise[*evm.WriteReportReply] {
+	f.calls = append(f.calls, req)
+	return cre.PromiseFromResult(&evm.WriteReportReply{}, nil)
+}
+
+// fakeCooldownStore is an in-memory CooldownStore for tests; the real store
+// round-trips through an on-chain registry contract (aegis/state.ContractKV).
+type fakeCooldownStore struct {
+	data map[string][]byte
+}
+
+func newFakeCooldownStore() *fakeCooldownStore {
+	return &fakeCooldownStore{data: map[string][]byte{}}
+}
+
+func (f *fakeCooldownStore) Get(_ cre.Runtime, key string) ([]byte, error) {
+	return f.data[key], nil
+}
+
+func (f *fakeCooldownStore) Set(_ cre.Runtime, key string, value []byte) error {
+	f.data[key] = value
+	return nil
+}
+
+func newTestSubmitter(t *testing.T, client reportWriter, store CooldownStore, cooldown int64) *Submitter {
+	t.Helper()
+	cfg := &Config{
+		ChainSelector:   5009297550715157269,
+		ContractAddress: "0x00000000000000000000000000000000000000aa",
+		CooldownSeconds: cooldown,
+	}
+	sub, err := NewSubmitter(cfg, nil, store)
+	if err != nil {
+		t.Fatalf("NewSubmitter: %v", err)
+	}
+	sub.client = client
+	return sub
+}
+
+func TestSubmitIfTripped_SkipsWhenNotTripped(t *testing.T) {
+	writer := &fakeReportWriter{}
+	sub := newTestSubmitter(t, writer, newFakeCooldownStore(), 60)
+	runtime := testutils.NewRuntime(t, nil)
+
+	err := sub.SubmitIfTripped(runtime, RiskReport{
+		Timestamp:      time.Unix(1000, 0),
+		CircuitBreaker: false,
+	})
+	if err != nil {
+		t.Fatalf("SubmitIfTripped: %v", err)
+	}
+	if len(writer.calls) != 0 {
+		t.Fatalf("expected no on-chain submission, got %d", len(writer.calls))
+	}
+}
+
+func TestSubmitIfTripped_SubmitsOnTrip(t *testing.T) {
+	writer := &fakeReportWriter{}
+	sub := newTestSubmitter(t, writer, newFakeCooldownStore(), 60)
+	runtime := testutils.NewRuntime(t, nil)
+
+	err := sub.SubmitIfTripped(runtime, RiskReport{
+		Timestamp:      time.Unix(1000, 0),
+		RiskScore:      95,
+		CircuitBreaker: true,
+	})
+	if err != nil {
+		t.Fatalf("SubmitIfTripped: %v", err)
+	}
+	if len(writer.calls) != 1 {
+		t.Fatalf("expected one on-chain submission, got %d", len(writer.calls))
+	}
+}
+
+// TestSubmitIfTripped_RespectsCooldown exercises the cooldown the way
+// workflow.go actually calls it: a brand-new Submitter built fresh for each
+// tick, sharing only the CooldownStore in between - not one long-lived
+// Submitter instance. That's what makes the cooldown real: the window has
+// to survive a new Submitter being constructed every invocation.
+func TestSubmitIfTripped_RespectsCooldown(t *testing.T) {
+	writer := &fakeReportWriter{}
+	store := newFakeCooldownStore()
+	runtime := testutils.NewRuntime(t, nil)
+
+	first := RiskReport{Timestamp: time.Unix(1000, 0), RiskScore: 95, CircuitBreaker: true}
+	second := RiskReport{Timestamp: time.Unix(1100, 0), RiskScore: 95, CircuitBreaker: true}
+
+	firstSub := newTestSubmitter(t, writer, store, 300)
+	if err := firstSub.SubmitIfTripped(runtime, first); err != nil {
+		t.Fatalf("SubmitIfTripped (first): %v", err)
+	}
+
+	secondSub := newTestSubmitter(t, writer, store, 300)
+	if err := secondSub.SubmitIfTripped(runtime, second); err != nil {
+		t.Fatalf("SubmitIfTripped (second): %v", err)
+	}
+
+	if len(writer.calls) != 1 {
+		t.Fatalf("expected cooldown to suppress the second submission across a fresh Submitter, got %d calls", len(writer.calls))
+	}
+}
+
+func TestSubmitIfTripped_AllowsAfterCooldownElapses(t *testing.T) {
+	writer := &fakeReportWriter{}
+	store := newFakeCooldownStore()
+	runtime := testutils.NewRuntime(t, nil)
+
+	first := RiskReport{Timestamp: time.Unix(1000, 0), RiskScore: 95, CircuitBreaker: true}
+	later := RiskReport{Timestamp: time.Unix(1400, 0), RiskScore: 95, CircuitBreaker: true}
+
+	firstSub := newTestSubmitter(t, writer, store, 300)
+	if err := firstSub.SubmitIfTripped(runtime, first); err != nil {
+		t.Fatalf("SubmitIfTripped (first): %v", err)
+	}
+
+	laterSub := newTestSubmitter(t, writer, store, 300)
+	if err := laterSub.SubmitIfTripped(runtime, later); err != nil {
+		t.Fatalf("SubmitIfTripped (later): %v", err)
+	}
+
+	if len(writer.calls) != 2 {
+		t.Fatalf("expected the cooldown to have elapsed, got %d calls", len(writer.calls))
+	}
+}
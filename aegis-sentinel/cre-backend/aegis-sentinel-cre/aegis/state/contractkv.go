@@ -0,0 +1,128 @@
+// Package state provides the one cross-invocation persistence mechanism
+// actually available to CRE workflows: on-chain contract storage. CRE
+// callbacks are stateless per invocation and cre-sdk-go has no
+// consensus-backed key/value capability, so anything that needs to survive
+// between ticks (an EWMA baseline, a pending Merkle batch) has to round-trip
+// through a small on-chain registry contract instead.
+package state
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/smartcontractkit/cre-sdk-go/capabilities/blockchain/evm"
+	"github.com/smartcontractkit/cre-sdk-go/cre"
+)
+
+// registryABI describes the on-chain key/value registry this package talks
+// to: get(bytes32) is a plain view call, set(bytes32,bytes) is only ever
+// invoked through a DON-signed report so every node agrees on what gets
+// written before the registry accepts it.
+const registryABI = `[
+	{"name":"get","type":"function","stateMutability":"view","inputs":[{"name":"key","type":"bytes32"}],"outputs":[{"name":"value","type":"bytes"}]},
+	{"name":"set","type":"function","inputs":[{"name":"key","type":"bytes32"},{"name":"value","type":"bytes"}]}
+]`
+
+// ContractKV persists small state blobs in an on-chain registry contract.
+// Reads are plain CallContract view calls; writes go through the same
+// DON-signed report + forwarder pathway used for on-chain submissions
+// elsewhere in this repo (see aegis/onchain), so a write is only durable
+// once 2f+1 nodes have agreed on it.
+type ContractKV struct {
+	client *evm.Client
+	abi    abi.ABI
+	addr   common.Address
+}
+
+// NewContractKV builds a ContractKV bound to the registry contract at
+// contractAddress on chainSelector.
+func NewContractKV(chainSelector uint64, contractAddress string) (*ContractKV, error) {
+	parsed, err := abi.JSON(bytes.NewReader([]byte(registryABI)))
+	if err != nil {
+		return nil, fmt.Errorf("state: parse registry ABI: %w", err)
+	}
+	if !common.IsHexAddress(contractAddress) {
+		return nil, fmt.Errorf("state: invalid contract address %q", contractAddress)
+	}
+	return &ContractKV{
+		client: &evm.Client{ChainSelector: chainSelector},
+		abi:    parsed,
+		addr:   common.HexToAddress(contractAddress),
+	}, nil
+}
+
+// Get returns the value stored under key, or nil if it has never been set.
+func (kv *ContractKV) Get(runtime cre.Runtime, key string) ([]byte, error) {
+	calldata, err := kv.abi.Pack("get", keyHash(key))
+	if err != nil {
+		return nil, fmt.Errorf("state: pack get calldata: %w", err)
+	}
+
+	reply, err := kv.client.CallContract(runtime, &evm.CallContractRequest{
+		Call: &evm.CallMsg{To: kv.addr.Bytes(), Data: calldata},
+	}).Await()
+	if err != nil {
+		return nil, fmt.Errorf("state: call get: %w", err)
+	}
+	if len(reply.Data) == 0 {
+		return nil, nil
+	}
+
+	var value []byte
+	if err := kv.abi.UnpackIntoInterface(&value, "get", reply.Data); err != nil {
+		return nil, fmt.Errorf("state: unpack get reply: %w", err)
+	}
+	return value, nil
+}
+
+// Set writes value under key through the DON-signed report pathway: every
+// node encodes the same (key, value) pair, the DON signs it once 2f+1 nodes
+// agree, and the registry contract's forwarder-facing receiver decodes and
+// stores it.
+func (kv *ContractKV) Set(runtime cre.Runtime, key string, value []byte) error {
+	args := abi.Arguments{{Type: bytes32Type}, {Type: bytesType}}
+	encodedPayload, err := args.Pack(keyHash(key), value)
+	if err != nil {
+		return fmt.Errorf("state: encode set payload: %w", err)
+	}
+
+	signed, err := runtime.GenerateReport(&cre.ReportRequest{
+		EncodedPayload: encodedPayload,
+		EncoderName:    "abi",
+		SigningAlgo:    "ecdsa-secp256k1",
+		HashingAlgo:    "keccak256",
+	}).Await()
+	if err != nil {
+		return fmt.Errorf("state: generate DON-signed report: %w", err)
+	}
+
+	if _, err := kv.client.WriteReport(runtime, &evm.WriteCreReportRequest{
+		Receiver: kv.addr.Bytes(),
+		Report:   signed,
+	}).Await(); err != nil {
+		return fmt.Errorf("state: submit set transaction: %w", err)
+	}
+	return nil
+}
+
+var (
+	bytes32Type = mustType("bytes32")
+	bytesType   = mustType("bytes")
+)
+
+func mustType(name string) abi.Type {
+	t, err := abi.NewType(name, "", nil)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// keyHash maps a human-readable key onto the bytes32 slot the registry
+// contract indexes by.
+func keyHash(key string) [32]byte {
+	return crypto.Keccak256Hash([]byte(key))
+}
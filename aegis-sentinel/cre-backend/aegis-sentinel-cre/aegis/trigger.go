@@ -0,0 +1,108 @@
+// Package aegis broadcasts this workflow's risk reports to in-process
+// subscribers as each DON node computes them.
+//
+// There is no generic remote-trigger capability in cre-sdk-go that another
+// workflow could bind to cross-process the way cron.Trigger or
+// evm.LogTrigger do for their own capabilities - triggers are concrete,
+// generated capability clients, and "aegis-risk-trigger" isn't one of
+// them. Until that capability exists on the DON, Publisher.Register is an
+// in-process subscription API only: each DON node runs this workflow's
+// Callback (and its own Publisher) in a separate process, so Publish only
+// ever fans a report out to subscribers registered in that same process.
+package aegis
+
+import "sync"
+
+// RiskReport is the payload other workflows receive. It mirrors the
+// publishing workflow's AegisRiskReport so subscribers don't need to import
+// a `main` package.
+type RiskReport struct {
+	Timestamp      string `json:"timestamp"`
+	EthPrice       string `json:"ethPrice"`
+	RiskScore      int    `json:"riskScore"`
+	AnomalyLevel   string `json:"anomalyLevel"`
+	CircuitBreaker bool   `json:"circuitBreaker"`
+}
+
+// RiskFilter controls which published reports a subscriber receives.
+type RiskFilter struct {
+	MinScore         int  `json:"minScore"`
+	OnCircuitBreaker bool `json:"onCircuitBreaker"`
+}
+
+// Matches reports whether report passes filter. A nil filter matches
+// everything.
+func (f *RiskFilter) Matches(report RiskReport) bool {
+	if f == nil {
+		return true
+	}
+	if f.OnCircuitBreaker && report.CircuitBreaker {
+		return true
+	}
+	return report.RiskScore >= f.MinScore
+}
+
+// subscription is one registered handler.
+type subscription struct {
+	filter *RiskFilter
+	handle func(RiskReport)
+}
+
+// Publisher fans a report out to every in-process subscriber as soon as
+// Publish is called. It doesn't attempt cross-node agreement: each DON node
+// owns its own Publisher instance, so there's nothing here for separate
+// processes to reconcile.
+type Publisher struct {
+	workflowID string
+
+	mu   sync.Mutex
+	subs map[string]subscription
+}
+
+// NewPublisher builds a Publisher for workflowID.
+func NewPublisher(workflowID string) *Publisher {
+	return &Publisher{
+		workflowID: workflowID,
+		subs:       map[string]subscription{},
+	}
+}
+
+// PublisherConfig is the JSON-configurable shape of a Publisher. It's empty
+// for now; reserved so workflow.go's Config can keep a stable field if
+// Publisher grows options later.
+type PublisherConfig struct{}
+
+// NewPublisherFromConfig builds a Publisher from cfg.
+func NewPublisherFromConfig(workflowID string, _ *PublisherConfig) *Publisher {
+	return NewPublisher(workflowID)
+}
+
+// Register adds a subscriber handler that's invoked with every published
+// report matching filter.
+func (p *Publisher) Register(id string, filter *RiskFilter, handle func(RiskReport)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.subs[id] = subscription{filter: filter, handle: handle}
+}
+
+// Deregister removes a previously registered subscriber.
+func (p *Publisher) Deregister(id string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.subs, id)
+}
+
+// Publish delivers report to every subscriber whose filter matches. Returns
+// true if at least one subscriber received it.
+func (p *Publisher) Publish(report RiskReport) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delivered := false
+	for _, sub := range p.subs {
+		if sub.filter.Matches(report) {
+			sub.handle(report)
+			delivered = true
+		}
+	}
+	return delivered
+}
@@ -0,0 +1,58 @@
+package aegis
+
+import (
+	"testing"
+)
+
+func TestPublish_DeliversToMatchingSubscriber(t *testing.T) {
+	pub := NewPublisher("wf-1")
+
+	var received []RiskReport
+	pub.Register("sub", nil, func(r RiskReport) { received = append(received, r) })
+
+	fired := pub.Publish(RiskReport{Timestamp: "t1", RiskScore: 90, CircuitBreaker: true})
+	if !fired {
+		t.Fatal("expected Publish to report delivery when a subscriber is registered")
+	}
+	if len(received) != 1 {
+		t.Fatalf("expected exactly one delivery, got %d", len(received))
+	}
+}
+
+func TestPublish_RespectsFilter(t *testing.T) {
+	pub := NewPublisher("wf-1")
+
+	delivered := 0
+	pub.Register("sub", &RiskFilter{MinScore: 50}, func(RiskReport) { delivered++ })
+
+	pub.Publish(RiskReport{Timestamp: "t1", RiskScore: 10})
+	if delivered != 0 {
+		t.Fatalf("expected low-score report to be filtered out, got %d deliveries", delivered)
+	}
+
+	pub.Publish(RiskReport{Timestamp: "t2", RiskScore: 80})
+	if delivered != 1 {
+		t.Fatalf("expected high-score report to pass the filter, got %d deliveries", delivered)
+	}
+}
+
+func TestPublish_NoSubscribersReturnsFalse(t *testing.T) {
+	pub := NewPublisher("wf-1")
+
+	if pub.Publish(RiskReport{Timestamp: "t1", RiskScore: 90}) {
+		t.Fatal("expected no delivery when nothing is registered")
+	}
+}
+
+func TestDeregister_StopsFurtherDelivery(t *testing.T) {
+	pub := NewPublisher("wf-1")
+
+	delivered := 0
+	pub.Register("sub", nil, func(RiskReport) { delivered++ })
+	pub.Deregister("sub")
+
+	pub.Publish(RiskReport{Timestamp: "t1", RiskScore: 90})
+	if delivered != 0 {
+		t.Fatalf("expected no delivery after deregistration, got %d", delivered)
+	}
+}